@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// APICredentials holds everything readAPIAuthCredentials can load from the
+// credentials file. A Vonage account is authenticated either with
+// APIKey/APISecret (the older APIs) or with ApplicationID/PrivateKey (JWT,
+// required by the newer Messages/Voice/Conversations APIs).
+type APICredentials struct {
+	APIKey        string
+	APISecret     string
+	ApplicationID string `json:"application_id"`
+	PrivateKey    string `json:"private_key"`
+}
+
+// credentialsFile is the on-disk shape of /app/credentials/nexmo.json in
+// multi-tenant mode: one named set of credentials per Vonage (sub)account.
+type credentialsFile struct {
+	Accounts map[string]APICredentials `json:"accounts"`
+}
+
+/*
+* Retrieves one set of API credentials per configured account, from file,
+* or error. For backwards compatibility, a file that isn't wrapped in an
+* "accounts" object is treated as a single account named "default".
+ */
+func readAPIAuthCredentials() (map[string]APICredentials, error) {
+	jsonData, err := ioutil.ReadFile("/app/credentials/nexmo.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API credentials: %v", err)
+	}
+
+	var file credentialsFile
+	if err := json.Unmarshal(jsonData, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Accounts) > 0 {
+		return file.Accounts, nil
+	}
+
+	var legacy APICredentials
+	if err := json.Unmarshal(jsonData, &legacy); err != nil {
+		return nil, err
+	}
+	return map[string]APICredentials{"default": legacy}, nil
+}
+
+// authMethod attaches Vonage API credentials to an outgoing request. query
+// is passed in so query-parameter-based auth can add to it before it's
+// encoded onto the request URL.
+type authMethod interface {
+	authenticate(req *http.Request, query url.Values) error
+}
+
+// newAuthMethod picks a JWT signer when an application ID and private key
+// are configured, falling back to the legacy api_key/api_secret query
+// parameters otherwise.
+func newAuthMethod(creds APICredentials) (authMethod, error) {
+	if creds.ApplicationID != "" && creds.PrivateKey != "" {
+		signer, err := newJWTSigner(creds.ApplicationID, []byte(creds.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up JWT auth: %v", err)
+		}
+		return jwtBearerAuth{signer: signer}, nil
+	}
+	return keySecretAuth{key: creds.APIKey, secret: creds.APISecret}, nil
+}
+
+// keySecretAuth is the legacy auth style: api_key/api_secret as query
+// parameters.
+type keySecretAuth struct {
+	key    string
+	secret string
+}
+
+func (a keySecretAuth) authenticate(req *http.Request, query url.Values) error {
+	query.Set("api_key", a.key)
+	query.Set("api_secret", a.secret)
+	return nil
+}
+
+// jwtBearerAuth signs a short-lived RS256 JWT per scrape (cached until
+// close to expiry) and attaches it as an Authorization: Bearer header.
+type jwtBearerAuth struct {
+	signer *jwtSigner
+}
+
+func (a jwtBearerAuth) authenticate(req *http.Request, query url.Values) error {
+	token, err := a.signer.token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
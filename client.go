@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/log"
+)
+
+// apiClient is a thin, shared HTTP client for talking to the Vonage/Nexmo
+// REST API. Individual collectors build their own request paths; get-balance
+// is the one endpoint that still needs the raw key/secret-in-path URL style,
+// everything else goes through get() which attaches whichever auth method
+// the account's credentials resolved to (see auth.go). Every request is
+// instrumented per endpoint and retried with backoff on 429/5xx responses.
+type apiClient struct {
+	baseURL   string
+	key       string
+	secret    string
+	auth      authMethod
+	http      *http.Client
+	transport http.RoundTripper
+
+	maxRetries     int
+	retryBaseDelay time.Duration
+
+	requestDuration *prometheus.HistogramVec
+	requestsTotal   *prometheus.CounterVec
+}
+
+func newAPIClient(baseURL string, creds APICredentials, namespace string, timeout time.Duration, maxRetries int, retryBaseDelay time.Duration) (*apiClient, error) {
+	auth, err := newAuthMethod(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{
+		baseURL:        baseURL,
+		key:            creds.APIKey,
+		secret:         creds.APISecret,
+		auth:           auth,
+		http:           &http.Client{Timeout: timeout},
+		transport:      http.DefaultTransport,
+		maxRetries:     maxRetries,
+		retryBaseDelay: retryBaseDelay,
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_request_duration_seconds",
+			Help:      "Duration of HTTP requests to the Vonage API, by endpoint and status code.",
+		}, []string{"endpoint", "code"}),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "Total HTTP requests made to the Vonage API, by endpoint and status code.",
+		}, []string{"endpoint", "code"}),
+	}, nil
+}
+
+// newAPIClients builds one long-lived apiClient per account, keyed by
+// account name. Callers that scrape the same account repeatedly (like
+// probeHandler) should reuse these rather than building a fresh apiClient
+// per scrape, since each one owns its own request-duration/requests-total
+// counters.
+//
+// An account whose credentials can't be turned into an apiClient (e.g. an
+// unparseable JWT private key) is logged and skipped rather than failing
+// the whole exporter: one misconfigured sub-account shouldn't take every
+// other account's /probe down with it.
+func newAPIClients(accounts map[string]APICredentials, apiURL, namespace string, timeout time.Duration, maxRetries int, retryBaseDelay time.Duration) (map[string]*apiClient, error) {
+	clients := make(map[string]*apiClient, len(accounts))
+	for name, creds := range accounts {
+		client, err := newAPIClient(apiURL, creds, namespace, timeout, maxRetries, retryBaseDelay)
+		if err != nil {
+			log.Errorf("account %q: skipping, failed to set up API client: %v", name, err)
+			continue
+		}
+		clients[name] = client
+	}
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no account could be set up")
+	}
+	return clients, nil
+}
+
+// get issues an authenticated GET against baseURL+path, attaching the
+// client's auth method (JWT bearer token, or api_key/api_secret query
+// parameters as a fallback) in addition to anything the caller already set
+// on query.
+func (c *apiClient) get(path string, query url.Values) ([]byte, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.auth.authenticate(req, query); err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = query.Encode()
+	req.Header.Add("Accept", "application/json")
+
+	return c.doWithRetry(req, path)
+}
+
+// getRaw issues a GET against a fully-formed URL, labeling its metrics with
+// endpoint (fullURL itself is never used as a label, since callers like the
+// balance collector embed credentials in it).
+func (c *apiClient) getRaw(fullURL, endpoint string) ([]byte, error) {
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	return c.doWithRetry(req, endpoint)
+}
+
+// doWithRetry executes req, retrying up to maxRetries times on 429/5xx
+// responses (and on transport errors) with exponential backoff and jitter,
+// honoring a Retry-After header when the API sends one.
+func (c *apiClient) doWithRetry(req *http.Request, endpoint string) ([]byte, error) {
+	httpClient := &http.Client{
+		Timeout:   c.http.Timeout,
+		Transport: c.instrumentedTransport(endpoint),
+	}
+
+	var lastErr error
+	var retryAfter string
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryDelay(c.retryBaseDelay, attempt, retryAfter))
+			retryAfter = ""
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		body, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			lastErr = readErr
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return body, nil
+		}
+
+		lastErr = fmt.Errorf("HTTP status %d", resp.StatusCode)
+		if !retryableStatus(resp.StatusCode) {
+			return nil, lastErr
+		}
+		retryAfter = resp.Header.Get("Retry-After")
+	}
+
+	return nil, lastErr
+}
+
+// instrumentedTransport wraps the client's base transport so every request
+// made through it records nexmo_api_request_duration_seconds and
+// nexmo_api_requests_total, labeled with this specific endpoint.
+func (c *apiClient) instrumentedTransport(endpoint string) http.RoundTripper {
+	duration := c.requestDuration.MustCurryWith(prometheus.Labels{"endpoint": endpoint})
+	counter := c.requestsTotal.MustCurryWith(prometheus.Labels{"endpoint": endpoint})
+
+	return promhttp.InstrumentRoundTripperCounter(counter,
+		promhttp.InstrumentRoundTripperDuration(duration, c.transport))
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || (code >= 500 && code < 600)
+}
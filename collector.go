@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Collector is implemented by every Nexmo API sub-collector (balance,
+// pricing, numbers, ...). Each collector owns a single Vonage endpoint (or
+// a small family of closely related ones) and is responsible for turning
+// the response into Prometheus metrics.
+type Collector interface {
+	// Update scrapes the collector's Vonage endpoint(s) using client and
+	// sends the resulting metrics to ch. A non-nil error marks the
+	// collector as failed for this scrape.
+	Update(client *apiClient, ch chan<- prometheus.Metric) error
+}
+
+type factoryFunc func(namespace string) Collector
+
+var (
+	factories      = make(map[string]factoryFunc)
+	collectorState = make(map[string]*bool)
+)
+
+// registerCollector wires a new collector up to a `--collector.<name>`
+// kingpin flag, following node_exporter's collector registration pattern.
+func registerCollector(name string, isDefaultEnabled bool, factory factoryFunc) {
+	helpDefaultState := "disabled"
+	if isDefaultEnabled {
+		helpDefaultState = "enabled"
+	}
+
+	flagName := fmt.Sprintf("collector.%s", name)
+	flagHelp := fmt.Sprintf("Enable the %s collector (default: %s).", name, helpDefaultState)
+	defaultValue := strconv.FormatBool(isDefaultEnabled)
+
+	flag := kingpin.Flag(flagName, flagHelp).Default(defaultValue).Bool()
+	collectorState[name] = flag
+	factories[name] = factory
+}
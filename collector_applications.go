@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type applicationsResp struct {
+	Count int `json:"count"`
+}
+
+// applicationsCollector scrapes GET /v2/applications and reports how many
+// Vonage applications exist on the account.
+type applicationsCollector struct {
+	total *prometheus.Desc
+}
+
+func newApplicationsCollector(namespace string) Collector {
+	return &applicationsCollector{
+		total: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "applications", "total"),
+			"Number of Vonage applications registered on the account.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *applicationsCollector) Update(client *apiClient, ch chan<- prometheus.Metric) error {
+	body, err := client.get("/v2/applications", nil)
+	if err != nil {
+		return err
+	}
+
+	var resp applicationsResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(resp.Count))
+	return nil
+}
+
+func init() {
+	registerCollector("applications", false, newApplicationsCollector)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type balanceResp struct {
+	Value      float64 `json:"value"`
+	AutoReload bool    `json:"autoReload"`
+}
+
+// balanceCollector scrapes GET /account/get-balance.
+type balanceCollector struct {
+	balance *prometheus.Desc
+}
+
+func newBalanceCollector(namespace string) Collector {
+	return &balanceCollector{
+		balance: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "balance"),
+			"Nexmo balance in euros.",
+			nil, nil,
+		),
+	}
+}
+
+// errBalanceRequiresKeySecret is returned when an account is configured for
+// JWT auth only; the Account API (unlike Messages/Voice/Conversations)
+// doesn't accept a JWT and needs api_key/api_secret.
+var errBalanceRequiresKeySecret = errors.New("balance collector requires api_key/api_secret credentials, not JWT")
+
+func (c *balanceCollector) Update(client *apiClient, ch chan<- prometheus.Metric) error {
+	if client.key == "" || client.secret == "" {
+		return errBalanceRequiresKeySecret
+	}
+
+	body, err := client.getRaw(client.baseURL+"/account/get-balance/"+client.key+"/"+client.secret, "/account/get-balance")
+	if err != nil {
+		return err
+	}
+
+	var resp balanceResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.balance, prometheus.GaugeValue, resp.Value)
+	return nil
+}
+
+func init() {
+	registerCollector("balance", true, newBalanceCollector)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type messagesResp struct {
+	Count int `json:"count"`
+	Items []struct {
+		Status string `json:"status"`
+	} `json:"items"`
+}
+
+// messagesCollector scrapes GET /search/messages for the last 24h of SMS
+// traffic and reports a count per delivery status.
+type messagesCollector struct {
+	total *prometheus.Desc
+}
+
+func newMessagesCollector(namespace string) Collector {
+	return &messagesCollector{
+		total: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "messages", "total"),
+			"Number of SMS messages sent in the last 24h, by delivery status.",
+			[]string{"status"}, nil,
+		),
+	}
+}
+
+func (c *messagesCollector) Update(client *apiClient, ch chan<- prometheus.Metric) error {
+	query := url.Values{"date": {time.Now().Add(-24 * time.Hour).Format("2006-01-02")}}
+
+	body, err := client.get("/search/messages", query)
+	if err != nil {
+		return err
+	}
+
+	var resp messagesResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	counts := make(map[string]float64)
+	for _, item := range resp.Items {
+		counts[item.Status]++
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, count, status)
+	}
+	return nil
+}
+
+func init() {
+	registerCollector("messages", false, newMessagesCollector)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type numbersResp struct {
+	Count   int `json:"count"`
+	Numbers []struct {
+		Country string `json:"country"`
+		Type    string `json:"type"`
+	} `json:"numbers"`
+}
+
+// numbersCollector scrapes GET /account/numbers, the account's inventory
+// of owned virtual numbers.
+type numbersCollector struct {
+	owned *prometheus.Desc
+}
+
+func newNumbersCollector(namespace string) Collector {
+	return &numbersCollector{
+		owned: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "numbers", "owned"),
+			"Number of owned Nexmo virtual numbers, by country and type.",
+			[]string{"country", "type"}, nil,
+		),
+	}
+}
+
+func (c *numbersCollector) Update(client *apiClient, ch chan<- prometheus.Metric) error {
+	body, err := client.get("/account/numbers", nil)
+	if err != nil {
+		return err
+	}
+
+	var resp numbersResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	counts := make(map[[2]string]float64)
+	for _, n := range resp.Numbers {
+		counts[[2]string{n.Country, n.Type}]++
+	}
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.owned, prometheus.GaugeValue, count, key[0], key[1])
+	}
+	return nil
+}
+
+func init() {
+	registerCollector("numbers", true, newNumbersCollector)
+}
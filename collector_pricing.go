@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var pricingCountries = kingpin.Flag(
+	"collector.pricing.countries",
+	"Comma-separated list of two-letter country codes to fetch outbound SMS pricing for.",
+).Default("US,GB").String()
+
+type pricingResp struct {
+	Price   string `json:"price"`
+	Country string `json:"country"`
+}
+
+// pricingCollector scrapes GET /account/get-pricing/outbound/sms for a
+// configurable set of countries.
+type pricingCollector struct {
+	smsPrice *prometheus.Desc
+	errors   *prometheus.Desc
+}
+
+func newPricingCollector(namespace string) Collector {
+	return &pricingCollector{
+		smsPrice: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pricing", "sms_price"),
+			"Outbound SMS price in euros for a given country.",
+			[]string{"country"}, nil,
+		),
+		errors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "pricing", "error"),
+			"Whether fetching pricing for a country failed on the last scrape.",
+			[]string{"country"}, nil,
+		),
+	}
+}
+
+// Update fetches pricing for every configured country independently, so one
+// broken country doesn't discard metrics for the rest: each failure is
+// reported via nexmo_pricing_error{country} instead of aborting the loop,
+// and the collector itself only fails once every country has. This is a
+// gauge, not a counter: pricingCollector is rebuilt fresh on every /probe
+// call (unlike apiClient, which is kept long-lived across probes), so a
+// per-scrape counter would never actually accumulate.
+func (c *pricingCollector) Update(client *apiClient, ch chan<- prometheus.Metric) error {
+	var attempted, failed int
+	var errs []string
+
+	for _, country := range strings.Split(*pricingCountries, ",") {
+		country = strings.TrimSpace(country)
+		if country == "" {
+			continue
+		}
+		attempted++
+
+		if err := c.updateCountry(client, ch, country); err != nil {
+			failed++
+			errs = append(errs, fmt.Sprintf("%s: %v", country, err))
+			ch <- prometheus.MustNewConstMetric(c.errors, prometheus.GaugeValue, 1, country)
+		}
+	}
+
+	if attempted > 0 && failed == attempted {
+		return fmt.Errorf("all countries failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *pricingCollector) updateCountry(client *apiClient, ch chan<- prometheus.Metric, country string) error {
+	body, err := client.get("/account/get-pricing/outbound/sms/"+country, nil)
+	if err != nil {
+		return err
+	}
+
+	var resp pricingResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	price, err := strconv.ParseFloat(resp.Price, 64)
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.smsPrice, prometheus.GaugeValue, price, country)
+	return nil
+}
+
+func init() {
+	registerCollector("pricing", true, newPricingCollector)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type callsResp struct {
+	CountTotal int `json:"count_total"`
+	Embedded   struct {
+		Calls []struct {
+			Status    string `json:"status"`
+			Direction string `json:"direction"`
+		} `json:"calls"`
+	} `json:"_embedded"`
+}
+
+// voiceCollector scrapes GET /v1/calls for the last 24h of voice traffic
+// and reports a count per status/direction pair.
+//
+// The Voice API requires a JWT-signed Authorization header rather than the
+// api_key/api_secret query parameters used elsewhere; client.get attaches
+// whichever authMethod the account's credentials resolved to (see auth.go),
+// so this collector needs an account configured with application_id/
+// private_key to succeed.
+type voiceCollector struct {
+	total *prometheus.Desc
+}
+
+func newVoiceCollector(namespace string) Collector {
+	return &voiceCollector{
+		total: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "calls", "total"),
+			"Number of voice calls in the last 24h, by status and direction.",
+			[]string{"status", "direction"}, nil,
+		),
+	}
+}
+
+func (c *voiceCollector) Update(client *apiClient, ch chan<- prometheus.Metric) error {
+	query := url.Values{"date_start": {time.Now().Add(-24 * time.Hour).Format(time.RFC3339)}}
+
+	body, err := client.get("/v1/calls", query)
+	if err != nil {
+		return err
+	}
+
+	var resp callsResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+
+	counts := make(map[[2]string]float64)
+	for _, call := range resp.Embedded.Calls {
+		counts[[2]string{call.Status, call.Direction}]++
+	}
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, count, key[0], key[1])
+	}
+	return nil
+}
+
+func init() {
+	registerCollector("voice", false, newVoiceCollector)
+}
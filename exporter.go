@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// Exporter coordinates the individual Vonage API collectors and exposes
+// them as a single prometheus.Collector.
+type Exporter struct {
+	client     *apiClient
+	collectors map[string]Collector
+
+	mutex sync.Mutex
+
+	up             prometheus.Gauge
+	totalScrapes   prometheus.Counter
+	scrapeDuration *prometheus.GaugeVec
+	scrapeSuccess  *prometheus.GaugeVec
+}
+
+// NewExporter returns an initialized Exporter with one sub-collector per
+// enabled `--collector.<name>` flag, backed by a freshly created apiClient.
+func NewExporter(apiUrl string, creds APICredentials, namespace string, timeout time.Duration, maxRetries int, retryBaseDelay time.Duration) (*Exporter, error) {
+	client, err := newAPIClient(apiUrl, creds, namespace, timeout, maxRetries, retryBaseDelay)
+	if err != nil {
+		return nil, err
+	}
+	return newExporterForClient(client, namespace)
+}
+
+// newExporterForClient is like NewExporter, but reuses an existing
+// apiClient instead of creating one. This matters for callers like
+// probeHandler that scrape the same account repeatedly: the client (and
+// the request-duration/requests-total counters it owns) needs to live
+// across scrapes, or those counters would reset on every single probe.
+func newExporterForClient(client *apiClient, namespace string) (*Exporter, error) {
+	collectors := make(map[string]Collector)
+	var enabled []string
+	for name, flag := range collectorState {
+		if !*flag {
+			continue
+		}
+		collectors[name] = factories[name](namespace)
+		enabled = append(enabled, name)
+	}
+	if len(collectors) == 0 {
+		return nil, fmt.Errorf("no collectors enabled")
+	}
+	sort.Strings(enabled)
+	log.Infof("Enabled collectors: %s", strings.Join(enabled, ", "))
+
+	return &Exporter{
+		client:     client,
+		collectors: collectors,
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "up",
+			Help:      "Was the last scrape of nexmo successful.",
+		}),
+		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "exporter_total_scrapes",
+			Help:      "Current total nexmo scrapes.",
+		}),
+		scrapeDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scrape_duration_seconds",
+			Help:      "Duration of a collector scrape.",
+		}, []string{"collector"}),
+		scrapeSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scrape_success",
+			Help:      "Whether a collector's last scrape succeeded.",
+		}, []string{"collector"}),
+	}, nil
+}
+
+/* Prometheus ingerface implementation */
+
+// Describe describes the exporter's own metrics. Per-collector metrics are
+// deliberately not described here (they vary by endpoint and by account),
+// which makes this an "unchecked" collector for that part of its output -
+// the same approach node_exporter uses for its per-collector metrics.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.up.Desc()
+	ch <- e.totalScrapes.Desc()
+	e.scrapeDuration.Describe(ch)
+	e.scrapeSuccess.Describe(ch)
+	e.client.requestDuration.Describe(ch)
+	e.client.requestsTotal.Describe(ch)
+}
+
+// Collect fetches the stats and delivers them as Prometheus metrics.
+// It implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mutex.Lock() // To protect metrics from concurrent collects.
+	defer e.mutex.Unlock()
+
+	e.scrape(ch)
+
+	ch <- e.up
+	ch <- e.totalScrapes
+	e.scrapeDuration.Collect(ch)
+	e.scrapeSuccess.Collect(ch)
+	e.client.requestDuration.Collect(ch)
+	e.client.requestsTotal.Collect(ch)
+}
+
+// scrape runs every enabled collector in its own goroutine so a slow
+// endpoint can't block the others, then waits for all of them to finish.
+func (e *Exporter) scrape(ch chan<- prometheus.Metric) {
+	e.totalScrapes.Inc()
+
+	var anyUp int32
+	var wg sync.WaitGroup
+	wg.Add(len(e.collectors))
+	for name, c := range e.collectors {
+		go func(name string, c Collector) {
+			defer wg.Done()
+			if e.runCollector(name, c, ch) {
+				atomic.StoreInt32(&anyUp, 1)
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	if anyUp == 1 {
+		e.up.Set(1)
+	} else {
+		e.up.Set(0)
+	}
+}
+
+// runCollector scrapes a single collector, recording its duration and
+// success as nexmo_scrape_duration_seconds/nexmo_scrape_success, and
+// reports whether it succeeded.
+func (e *Exporter) runCollector(name string, c Collector, ch chan<- prometheus.Metric) bool {
+	start := time.Now()
+	err := c.Update(e.client, ch)
+	duration := time.Since(start).Seconds()
+
+	success := 1.0
+	if err != nil {
+		log.Errorf("collector %s failed after %fs: %v", name, duration, err)
+		success = 0
+	}
+
+	e.scrapeDuration.WithLabelValues(name).Set(duration)
+	e.scrapeSuccess.WithLabelValues(name).Set(success)
+
+	return err == nil
+}
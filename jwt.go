@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// jwtTTL is how long a signed Vonage JWT is valid for; jwtRefreshSkew is
+// how long before expiry we proactively sign a new one.
+const (
+	jwtTTL         = 15 * time.Minute
+	jwtRefreshSkew = 30 * time.Second
+)
+
+// jwtSigner signs short-lived RS256 JWTs for the Vonage Messages/Voice/
+// Conversations APIs, caching the result until it's close to expiry so we
+// don't re-sign on every scrape.
+type jwtSigner struct {
+	applicationID string
+	privateKey    *rsa.PrivateKey
+
+	mutex       sync.Mutex
+	cachedToken string
+	expiresAt   time.Time
+}
+
+// newJWTSigner parses a PEM-encoded RSA private key (PKCS#1 or PKCS#8) for
+// the given Vonage application.
+func newJWTSigner(applicationID string, pemKey []byte) (*jwtSigner, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("no PEM data found in private key")
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %v", err)
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not an RSA key")
+		}
+		key = rsaKey
+	}
+
+	return &jwtSigner{applicationID: applicationID, privateKey: key}, nil
+}
+
+// token returns a cached JWT if it's not within jwtRefreshSkew of expiry,
+// or signs and caches a fresh one otherwise.
+func (s *jwtSigner) token() (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.cachedToken != "" && time.Now().Before(s.expiresAt.Add(-jwtRefreshSkew)) {
+		return s.cachedToken, nil
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(jwtTTL)
+	claims := jwt.MapClaims{
+		"application_id": s.applicationID,
+		"iat":            now.Unix(),
+		"exp":            expiresAt.Unix(),
+		"jti":            fmt.Sprintf("%s-%d", s.applicationID, now.UnixNano()),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	s.cachedToken = signed
+	s.expiresAt = expiresAt
+	return s.cachedToken, nil
+}
@@ -1,155 +1,15 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
-	"sync"
-	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
 	"github.com/prometheus/common/log"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
-// Exporter collects Nexmo stats and exports them using
-// the prometheus metrics package.
-type Exporter struct {
-	URI    string
-	client http.Client
-
-	mutex        sync.RWMutex
-	up           prometheus.Gauge
-	totalScrapes prometheus.Counter
-	balance      prometheus.Gauge
-}
-
-// NewExporter returns an initialized Exporter.
-func NewExporter(apiUrl, key, secret, namespace string, timeout time.Duration) (*Exporter, error) {
-	uri := apiUrl + "/account/get-balance/" + key + "/" + secret
-
-	return &Exporter{
-		URI:    uri,
-		client: http.Client{Timeout: timeout},
-		up: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "up",
-			Help:      "Was the last scrape of nexmo successful.",
-		}),
-		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "exporter_total_scrapes",
-			Help:      "Current total nexmo scrapes.",
-		}),
-		balance: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "balance",
-			Help:      "Nexmo balance in euros.",
-		}),
-	}, nil
-}
-
-/* Prometheus ingerface implementation */
-
-// Describe describes all the metrics. Implements prometheus.Collector.
-func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
-	ch <- e.up.Desc()
-	ch <- e.totalScrapes.Desc()
-	ch <- e.balance.Desc()
-}
-
-// Collect fetches the stats and delivers them as Prometheus metrics.
-// It implements prometheus.Collector.
-func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
-	e.mutex.Lock() // To protect metrics from concurrent collects.
-	defer e.mutex.Unlock()
-
-	e.scrape()
-
-	ch <- e.up
-	ch <- e.totalScrapes
-	ch <- e.balance
-}
-
-func (e *Exporter) scrape() {
-	e.totalScrapes.Inc()
-
-	balance, err := e.getBalance()
-	if err != nil {
-		e.up.Set(0)
-		log.Errorf("Can't get balance: %v", err)
-		return
-	}
-
-	e.balance.Set(balance)
-	e.up.Set(1)
-}
-
-/* Nexmo API client implementation */
-
-type balanceResp struct {
-	Value      float64 `json:"value"`
-	AutoReload bool    `json:"autoReload"`
-}
-
-func (e *Exporter) getBalance() (float64, error) {
-	req, err := http.NewRequest("GET", e.URI, nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Add("Accept", "application/json")
-	resp, err := e.client.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return 0, fmt.Errorf("HTTP status %d", resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-
-	var balance balanceResp
-	if err := json.Unmarshal(body, &balance); err != nil {
-		return 0, err
-	}
-	return balance.Value, nil
-}
-
-/* Misc */
-
-/* Data structure to hold Nexmo API credentials */
-type APICredentials struct {
-	APIKey    string
-	APISecret string
-}
-
-/*
-* Retrieves API key - API secret tuple from file or error
- */
-func readAPIAuthCredentials() (APICredentials, error) {
-	jsonData, err := ioutil.ReadFile("/app/credentials/nexmo.json")
-
-	if err != nil {
-		log.Fatal("Failed to read API credentials: ", err)
-
-		return APICredentials{}, err
-	}
-
-	var credentialsData APICredentials
-	json.Unmarshal(jsonData, &credentialsData)
-
-	return credentialsData, nil
-
-}
-
 func main() {
 
 	var (
@@ -177,6 +37,36 @@ func main() {
 			"nexmo.namespace",
 			"Prometheus namespace for Nexmo metrics",
 		).Default("nexmo").String()
+
+		pushGatewayURL = kingpin.Flag(
+			"push.gateway-url",
+			"Push metrics to this Pushgateway URL instead of serving /metrics. Disables the HTTP listener when set.",
+		).Default("").String()
+
+		pushInterval = kingpin.Flag(
+			"push.interval",
+			"How often to push metrics to the Pushgateway.",
+		).Default("60s").Duration()
+
+		pushOnce = kingpin.Flag(
+			"push.once",
+			"Push metrics to the Pushgateway once and exit, instead of looping every --push.interval.",
+		).Default("false").Bool()
+
+		pushTarget = kingpin.Flag(
+			"push.target",
+			"Named account (see the credentials file's \"accounts\" map) to scrape and push in --push.gateway-url mode.",
+		).Default("default").String()
+
+		nexmoMaxRetries = kingpin.Flag(
+			"nexmo.max-retries",
+			"Number of times to retry a Vonage API request on 429/5xx responses.",
+		).Default("3").Int()
+
+		nexmoRetryBaseDelay = kingpin.Flag(
+			"nexmo.retry-base-delay",
+			"Base delay for exponential backoff between Vonage API request retries.",
+		).Default("200ms").Duration()
 	)
 
 	log.AddFlags(kingpin.CommandLine)
@@ -184,36 +74,50 @@ func main() {
 	kingpin.HelpFlag.Short('h')
 	kingpin.Parse()
 
-	// read API authentication credentials
-	apiCredentials, err := readAPIAuthCredentials()
+	// read API authentication credentials, one set per named account
+	accounts, err := readAPIAuthCredentials()
 
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
 
-	exporter, err := NewExporter(*nexmoApiUrl,
-		apiCredentials.APIKey,
-		apiCredentials.APISecret,
-		*nexmoNamespace,
-		*nexmoTimeout,
-	)
+	prometheus.MustRegister(prometheus.NewBuildInfoCollector())
 
+	if *pushGatewayURL != "" {
+		creds, ok := accounts[*pushTarget]
+		if !ok {
+			log.Fatalf("unknown push target account %q", *pushTarget)
+		}
+
+		exporter, err := NewExporter(*nexmoApiUrl, creds, *nexmoNamespace, *nexmoTimeout, *nexmoMaxRetries, *nexmoRetryBaseDelay)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pusher := push.New(*pushGatewayURL, "nexmo_exporter").Collector(exporter)
+		runPusher(pusher, *pushInterval, *pushOnce)
+		return
+	}
+
+	// One long-lived apiClient per account, reused across every /probe
+	// call so their request-duration/requests-total counters accumulate
+	// instead of resetting on each scrape.
+	clients, err := newAPIClients(accounts, *nexmoApiUrl, *nexmoNamespace, *nexmoTimeout, *nexmoMaxRetries, *nexmoRetryBaseDelay)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	prometheus.MustRegister(exporter)
-	prometheus.MustRegister(prometheus.NewBuildInfoCollector())
-
 	log.Infoln("Listening on", *telemetryPort)
 
 	http.Handle(*metricsPath, promhttp.Handler())
+	http.HandleFunc("/probe", probeHandler(clients, *nexmoNamespace))
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
              <head><title>Nexmo Exporter</title></head>
              <body>
              <h1>Nexmo Exporter</h1>
              <p><a href='` + *metricsPath + `'>Metrics</a></p>
+             <p><a href='/probe?target=default'>Probe</a></p>
              </body>
              </html>`))
 	})
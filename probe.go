@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// probeHandler returns a blackbox_exporter-style /probe?target=<account>
+// handler: it builds a fresh Exporter for the named account around that
+// account's long-lived apiClient, scrapes only that account, and serves
+// the result labeled with target=<account>. This lets one process monitor
+// many Vonage subaccounts via per-target Prometheus scrape configs,
+// instead of baking one account in at startup.
+//
+// clients must outlive the handler (one entry per account, reused across
+// calls) so their request-duration/requests-total counters accumulate
+// across scrapes instead of resetting on every probe.
+func probeHandler(clients map[string]*apiClient, namespace string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		if target == "" {
+			http.Error(w, "target parameter is required", http.StatusBadRequest)
+			return
+		}
+
+		client, ok := clients[target]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown target %q", target), http.StatusNotFound)
+			return
+		}
+
+		exporter, err := newExporterForClient(client, namespace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		registry := prometheus.NewRegistry()
+		prometheus.WrapRegistererWith(prometheus.Labels{"target": target}, registry).MustRegister(exporter)
+
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	}
+}
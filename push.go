@@ -0,0 +1,27 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/log"
+)
+
+// runPusher pushes the exporter's metrics to a Pushgateway. With once set it
+// pushes a single time and returns; otherwise it pushes every interval and
+// never returns.
+func runPusher(pusher *push.Pusher, interval time.Duration, once bool) {
+	if once {
+		if err := pusher.Push(); err != nil {
+			log.Fatal("Failed to push metrics: ", err)
+		}
+		return
+	}
+
+	for {
+		if err := pusher.Push(); err != nil {
+			log.Errorf("Failed to push metrics: %v", err)
+		}
+		time.Sleep(interval)
+	}
+}
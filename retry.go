@@ -0,0 +1,25 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// retryDelay returns how long to wait before the given retry attempt
+// (1-indexed). It honors a Retry-After header when the server sent one,
+// otherwise it applies exponential backoff with full jitter: a random
+// duration between 0 and base*2^attempt.
+func retryDelay(base time.Duration, attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+
+	backoff := base * time.Duration(int64(1)<<uint(attempt))
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}